@@ -0,0 +1,507 @@
+// Package apitest provides a fluent, chainable API for testing HTTP handlers
+// without spinning up a real network listener.
+package apitest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// APITest builds up a single HTTP request to be sent to a handler under test.
+type APITest struct {
+	handler   http.Handler
+	name      string
+	method    string
+	url       string
+	body      string
+	query     map[string]string
+	headers   map[string]string
+	cookies   map[string]string
+	basicAuth string
+	jar       http.CookieJar
+	mocks     []*Mock
+	debug     bool
+
+	hasMultipart    bool
+	multipartFields map[string]string
+	multipartFiles  map[string]MultipartFile
+}
+
+// defaultHost is used to give requests a host for cookie jar matching when
+// the caller supplies a bare path such as "/hello".
+const defaultHost = "http://apitest"
+
+// New creates a new APITest that drives requests straight into handler,
+// bypassing the network stack.
+func New(handler http.Handler) *APITest {
+	return &APITest{
+		handler: handler,
+		query:   map[string]string{},
+		headers: map[string]string{},
+		cookies: map[string]string{},
+	}
+}
+
+// Name sets a human readable name for the test, surfaced in failure output.
+func (a *APITest) Name(name string) *APITest {
+	a.name = name
+	return a
+}
+
+// Method sets the HTTP method and path for the request.
+func (a *APITest) Method(method, url string) *APITest {
+	a.method = method
+	a.url = url
+	return a
+}
+
+// Get sets the request method to GET for the given path.
+func (a *APITest) Get(url string) *APITest {
+	return a.Method(http.MethodGet, url)
+}
+
+// Post sets the request method to POST for the given path.
+func (a *APITest) Post(url string) *APITest {
+	return a.Method(http.MethodPost, url)
+}
+
+// Put sets the request method to PUT for the given path.
+func (a *APITest) Put(url string) *APITest {
+	return a.Method(http.MethodPut, url)
+}
+
+// Delete sets the request method to DELETE for the given path.
+func (a *APITest) Delete(url string) *APITest {
+	return a.Method(http.MethodDelete, url)
+}
+
+// Patch sets the request method to PATCH for the given path.
+func (a *APITest) Patch(url string) *APITest {
+	return a.Method(http.MethodPatch, url)
+}
+
+// Body sets the raw request body.
+func (a *APITest) Body(body string) *APITest {
+	a.body = body
+	return a
+}
+
+// Query adds query string parameters to the request URL.
+func (a *APITest) Query(params map[string]string) *APITest {
+	for k, v := range params {
+		a.query[k] = v
+	}
+	return a
+}
+
+// Headers adds headers to the outgoing request.
+func (a *APITest) Headers(headers map[string]string) *APITest {
+	for k, v := range headers {
+		a.headers[k] = v
+	}
+	return a
+}
+
+// Cookies adds cookies to the outgoing request.
+func (a *APITest) Cookies(cookies map[string]string) *APITest {
+	for k, v := range cookies {
+		a.cookies[k] = v
+	}
+	return a
+}
+
+// BasicAuth sets the Authorization header from a "username:password" string.
+func (a *APITest) BasicAuth(auth string) *APITest {
+	a.basicAuth = auth
+	return a
+}
+
+// Debug enables dumping of the full request and response to DebugOutput
+// once the request completes, for quick triage of failing assertions.
+func (a *APITest) Debug() *APITest {
+	a.debug = true
+	return a
+}
+
+// Mocks declares the outbound HTTP calls the handler under test is expected
+// to make, and the canned responses to return for them. See NewMock.
+func (a *APITest) Mocks(mocks ...*Mock) *APITest {
+	a.mocks = append(a.mocks, mocks...)
+	return a
+}
+
+// Expect finalises the request and returns a Response used to assert on the
+// handler's output. t may be a *testing.T, *testing.B, or any other
+// testing.TB, so the chain can be driven from Benchmark* functions, fuzz
+// targets, and wrapping test helpers.
+func (a *APITest) Expect(t testing.TB) *Response {
+	return &Response{apiTest: a, t: t}
+}
+
+// buildRequest constructs the *http.Request described by the APITest so far.
+func (a *APITest) buildRequest() (*http.Request, error) {
+	reqURL := a.url
+	if len(a.query) > 0 {
+		values := url.Values{}
+		for k, v := range a.query {
+			values.Set(k, v)
+		}
+		reqURL = reqURL + "?" + values.Encode()
+	}
+
+	body := a.body
+	contentType := ""
+	if a.hasMultipart {
+		encoded, ct, err := buildMultipartBody(a.multipartFields, a.multipartFiles)
+		if err != nil {
+			return nil, err
+		}
+		body, contentType = encoded, ct
+	}
+
+	req, err := http.NewRequest(a.method, reqURL, bytes.NewBufferString(body))
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	if a.jar != nil {
+		if req.URL.Host == "" {
+			absolute, err := url.Parse(defaultHost + reqURL)
+			if err != nil {
+				return nil, err
+			}
+			req.URL = absolute
+		}
+		for _, cookie := range a.jar.Cookies(req.URL) {
+			req.AddCookie(cookie)
+		}
+	}
+
+	for k, v := range a.headers {
+		req.Header.Set(k, v)
+	}
+	for name, value := range a.cookies {
+		req.AddCookie(&http.Cookie{Name: name, Value: value})
+	}
+	if a.basicAuth != "" {
+		parts := strings.SplitN(a.basicAuth, ":", 2)
+		username := parts[0]
+		var password string
+		if len(parts) == 2 {
+			password = parts[1]
+		}
+		req.SetBasicAuth(username, password)
+	}
+
+	return req, nil
+}
+
+// Response accumulates expectations about a handler's response and, on End,
+// runs the request and asserts them.
+type Response struct {
+	apiTest *APITest
+	t       testing.TB
+
+	// req and res are set directly by FromRecorder, bypassing apiTest's
+	// normal "build request, invoke handler" flow.
+	req *http.Request
+	res *http.Response
+
+	hasStatus   bool
+	wantStatus  int
+	hasBody     bool
+	wantBody    string
+	hasBodyText bool
+	wantText    string
+
+	wantHeaders    map[string]string
+	wantCookies    map[string]string
+	wantCookieKeys []string
+
+	goldenPath string
+
+	asserts   []func(*http.Response, *http.Request)
+	jsonPaths []jsonPathExpectation
+}
+
+type jsonPathExpectation struct {
+	path string
+	fn   func(interface{})
+}
+
+// name returns the name of the underlying APITest, or "" when the Response
+// was built directly from a recorder via FromRecorder.
+func (r *Response) name() string {
+	if r.apiTest == nil {
+		return ""
+	}
+	return r.apiTest.name
+}
+
+// Status asserts the response status code.
+func (r *Response) Status(status int) *Response {
+	r.hasStatus = true
+	r.wantStatus = status
+	return r
+}
+
+// Body asserts the response body. If both the expected and actual bodies are
+// valid JSON they are compared for structural equality, ignoring formatting
+// and key order; otherwise they are compared as plain strings.
+func (r *Response) Body(body string) *Response {
+	r.hasBody = true
+	r.wantBody = body
+	return r
+}
+
+// BodyText asserts the response body as an exact string match.
+func (r *Response) BodyText(text string) *Response {
+	r.hasBodyText = true
+	r.wantText = text
+	return r
+}
+
+// Headers asserts that the response contains the given header values.
+func (r *Response) Headers(headers map[string]string) *Response {
+	if r.wantHeaders == nil {
+		r.wantHeaders = map[string]string{}
+	}
+	for k, v := range headers {
+		r.wantHeaders[k] = v
+	}
+	return r
+}
+
+// Cookies asserts that the response sets the given cookie values.
+func (r *Response) Cookies(cookies map[string]string) *Response {
+	if r.wantCookies == nil {
+		r.wantCookies = map[string]string{}
+	}
+	for k, v := range cookies {
+		r.wantCookies[k] = v
+	}
+	return r
+}
+
+// CookiePresent asserts that the response sets a cookie with the given name,
+// regardless of its value.
+func (r *Response) CookiePresent(name string) *Response {
+	r.wantCookieKeys = append(r.wantCookieKeys, name)
+	return r
+}
+
+// Assert registers a custom assertion function run against the raw response
+// and request. Panics raised inside fn propagate to the caller of End.
+func (r *Response) Assert(fn func(res *http.Response, req *http.Request)) *Response {
+	r.asserts = append(r.asserts, fn)
+	return r
+}
+
+// JSONPath asserts on the value(s) matched by a JSONPath expression against
+// the response body.
+func (r *Response) JSONPath(path string, fn func(interface{})) *Response {
+	r.jsonPaths = append(r.jsonPaths, jsonPathExpectation{path: path, fn: fn})
+	return r
+}
+
+// Golden asserts the response body against the golden file at path. When
+// UpdateGolden is true the golden file is (re)written from the actual
+// response instead of compared against it; apitest does not register an
+// -update flag itself, see UpdateGolden.
+func (r *Response) Golden(path string) *Response {
+	r.goldenPath = path
+	return r
+}
+
+// End executes the request against the handler and runs all registered
+// expectations, failing the test via t.Fatalf on the first mismatch.
+func (r *Response) End() {
+	r.t.Helper()
+
+	req, res := r.req, r.res
+	if r.apiTest != nil {
+		builtReq, err := r.apiTest.buildRequest()
+		if err != nil {
+			r.t.Fatalf("%s: failed to build request: %s", r.name(), err)
+			return
+		}
+
+		if len(r.apiTest.mocks) > 0 {
+			restore := installMockTransport(r.t, r.apiTest.mocks)
+			defer restore()
+		}
+
+		recorder := httptest.NewRecorder()
+		r.apiTest.handler.ServeHTTP(recorder, builtReq)
+		req, res = builtReq, recorder.Result()
+
+		// The handler may have fully drained req.Body. buildRequest's body is
+		// always a *bytes.Buffer, so http.NewRequest populated GetBody; use it
+		// to hand later consumers (Debug, custom Assert funcs) a fresh body
+		// instead of whatever the handler left behind.
+		if req.GetBody != nil {
+			if freshBody, err := req.GetBody(); err == nil {
+				req.Body = freshBody
+			}
+		}
+
+		if r.apiTest.jar != nil {
+			r.apiTest.jar.SetCookies(req.URL, res.Cookies())
+		}
+
+		for _, m := range r.apiTest.mocks {
+			m.assertSatisfied(r.t)
+		}
+	}
+
+	if r.hasStatus && res.StatusCode != r.wantStatus {
+		r.t.Fatalf("%s: expected status %d, got %d", r.name(), r.wantStatus, res.StatusCode)
+	}
+
+	bodyBytes, err := io.ReadAll(res.Body)
+	if err != nil {
+		r.t.Fatalf("%s: failed to read response body: %s", r.name(), err)
+		return
+	}
+	res.Body = io.NopCloser(bytes.NewReader(bodyBytes))
+
+	if r.apiTest != nil && r.apiTest.debug {
+		dumpDebug(req, res, bodyBytes)
+	}
+
+	if r.goldenPath != "" {
+		r.assertGolden(bodyBytes)
+	}
+
+	if r.hasBody {
+		r.assertBody(bodyBytes)
+	}
+
+	if r.hasBodyText && string(bodyBytes) != r.wantText {
+		r.t.Fatalf("%s: expected body text %q, got %q", r.name(), r.wantText, string(bodyBytes))
+	}
+
+	for name, value := range r.wantHeaders {
+		if got := res.Header.Get(name); got != value {
+			r.t.Fatalf("%s: expected header %s=%q, got %q", r.name(), name, value, got)
+		}
+	}
+
+	if len(r.wantCookies) > 0 || len(r.wantCookieKeys) > 0 {
+		r.assertCookies(res)
+	}
+
+	for _, jp := range r.jsonPaths {
+		r.assertJSONPath(bodyBytes, jp)
+	}
+
+	for _, assertFn := range r.asserts {
+		assertFn(res, req)
+	}
+}
+
+func (r *Response) assertBody(actual []byte) {
+	r.t.Helper()
+
+	var wantJSON, gotJSON interface{}
+	wantErr := json.Unmarshal([]byte(r.wantBody), &wantJSON)
+	gotErr := json.Unmarshal(actual, &gotJSON)
+
+	if wantErr == nil && gotErr == nil {
+		if !reflect.DeepEqual(wantJSON, gotJSON) {
+			r.t.Fatalf("%s: expected body %s, got %s", r.name(), r.wantBody, string(actual))
+		}
+		return
+	}
+
+	if string(actual) != r.wantBody {
+		r.t.Fatalf("%s: expected body %q, got %q", r.name(), r.wantBody, string(actual))
+	}
+}
+
+// cookieAttributeNames are RFC 6265 Set-Cookie attributes, not cookies in
+// their own right, so parseSetCookiePairs skips them.
+var cookieAttributeNames = map[string]bool{
+	"path":     true,
+	"domain":   true,
+	"expires":  true,
+	"max-age":  true,
+	"secure":   true,
+	"httponly": true,
+	"samesite": true,
+}
+
+// parseSetCookiePairs extracts every "name=value" pair across all Set-Cookie
+// header values, skipping RFC 6265 attribute tokens (Path, Domain, Max-Age,
+// ...) that follow a cookie's own pair rather than naming another cookie.
+func parseSetCookiePairs(header http.Header) map[string]string {
+	pairs := map[string]string{}
+	for _, raw := range header.Values("Set-Cookie") {
+		for _, part := range strings.Split(raw, ";") {
+			part = strings.TrimSpace(part)
+			kv := strings.SplitN(part, "=", 2)
+			if len(kv) != 2 {
+				continue
+			}
+			name := strings.TrimSpace(kv[0])
+			if cookieAttributeNames[strings.ToLower(name)] {
+				continue
+			}
+			pairs[name] = strings.TrimSpace(kv[1])
+		}
+	}
+	return pairs
+}
+
+func (r *Response) assertCookies(res *http.Response) {
+	r.t.Helper()
+
+	cookies := parseSetCookiePairs(res.Header)
+
+	for name, value := range r.wantCookies {
+		got, ok := cookies[name]
+		if !ok {
+			r.t.Fatalf("%s: expected cookie %s to be present", r.name(), name)
+			return
+		}
+		if got != value {
+			r.t.Fatalf("%s: expected cookie %s=%q, got %q", r.name(), name, value, got)
+			return
+		}
+	}
+
+	for _, name := range r.wantCookieKeys {
+		if _, ok := cookies[name]; !ok {
+			r.t.Fatalf("%s: expected cookie %s to be present", r.name(), name)
+			return
+		}
+	}
+}
+
+func (r *Response) assertJSONPath(body []byte, jp jsonPathExpectation) {
+	r.t.Helper()
+
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		r.t.Fatalf("%s: failed to unmarshal response body for JSONPath %q: %s", r.name(), jp.path, err)
+		return
+	}
+
+	value, err := evalJSONPath(jp.path, data)
+	if err != nil {
+		r.t.Fatalf("%s: invalid JSONPath %q: %s", r.name(), jp.path, err)
+		return
+	}
+
+	jp.fn(value)
+}
@@ -181,6 +181,18 @@ func TestApiTest_MatchesResponseCookies(t *testing.T) {
 		End()
 }
 
+func TestParseSetCookiePairs_SkipsRFC6265Attributes(t *testing.T) {
+	header := http.Header{}
+	header.Add("Set-Cookie", "session=abc123; Path=/; Domain=example.com; Max-Age=3600; SameSite=Lax; Secure; HttpOnly")
+
+	got := parseSetCookiePairs(header)
+	want := map[string]string{"session": "abc123"}
+
+	if len(got) != len(want) || got["session"] != want["session"] {
+		t.Fatalf("expected only the cookie pair to survive, got %v", got)
+	}
+}
+
 func TestApiTest_MatchesResponseHeaders(t *testing.T) {
 	handler := http.NewServeMux()
 	handler.HandleFunc("/hello", func(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,36 @@
+package apitest
+
+import (
+	"net/http"
+	"testing"
+)
+
+// assertHello drives the chain through a wrapping helper, rather than a
+// *testing.T/*testing.B directly, to exercise Expect's testing.TB parameter.
+func assertHello(tb testing.TB, handler http.Handler) {
+	tb.Helper()
+	New(handler).
+		Get("/hello").
+		Expect(tb).
+		Status(http.StatusOK).
+		End()
+}
+
+func helloHandler() http.Handler {
+	handler := http.NewServeMux()
+	handler.HandleFunc("/hello", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	return handler
+}
+
+func TestApiTest_ExpectAcceptsWrappingHelper(t *testing.T) {
+	assertHello(t, helloHandler())
+}
+
+func BenchmarkApiTest_Expect(b *testing.B) {
+	handler := helloHandler()
+	for i := 0; i < b.N; i++ {
+		assertHello(b, handler)
+	}
+}
@@ -0,0 +1,110 @@
+package apitest
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// Options sets the request method to OPTIONS for the given path.
+func (a *APITest) Options(url string) *APITest {
+	return a.Method(http.MethodOptions, url)
+}
+
+// PreflightOrigin configures the request as a CORS preflight: it sets the
+// Origin, Access-Control-Request-Method, and, when provided,
+// Access-Control-Request-Headers headers.
+func (a *APITest) PreflightOrigin(origin, method string, headers ...string) *APITest {
+	a.Headers(map[string]string{
+		"Origin":                        origin,
+		"Access-Control-Request-Method": method,
+	})
+	if len(headers) > 0 {
+		a.Headers(map[string]string{
+			"Access-Control-Request-Headers": strings.Join(headers, ", "),
+		})
+	}
+	return a
+}
+
+// AllowMethods asserts that the response's Allow or
+// Access-Control-Allow-Methods header lists exactly the given methods,
+// regardless of order or whitespace.
+func (r *Response) AllowMethods(methods ...string) *Response {
+	r.asserts = append(r.asserts, func(res *http.Response, req *http.Request) {
+		header := res.Header.Get("Access-Control-Allow-Methods")
+		if header == "" {
+			header = res.Header.Get("Allow")
+		}
+		assertHeaderSet(r.t, "Allow-Methods", header, methods)
+	})
+	return r
+}
+
+// AllowOrigin asserts the Access-Control-Allow-Origin header matches origin.
+func (r *Response) AllowOrigin(origin string) *Response {
+	r.asserts = append(r.asserts, func(res *http.Response, req *http.Request) {
+		r.t.Helper()
+		got := res.Header.Get("Access-Control-Allow-Origin")
+		if got != origin {
+			r.t.Fatalf("%s: expected Access-Control-Allow-Origin %q, got %q", r.name(), origin, got)
+		}
+	})
+	return r
+}
+
+// AllowHeaders asserts that the Access-Control-Allow-Headers header lists
+// exactly the given headers, regardless of order or whitespace.
+func (r *Response) AllowHeaders(headers ...string) *Response {
+	r.asserts = append(r.asserts, func(res *http.Response, req *http.Request) {
+		assertHeaderSet(r.t, "Allow-Headers", res.Header.Get("Access-Control-Allow-Headers"), headers)
+	})
+	return r
+}
+
+// AllowCredentials asserts the Access-Control-Allow-Credentials header
+// matches the given boolean value.
+func (r *Response) AllowCredentials(allow bool) *Response {
+	r.asserts = append(r.asserts, func(res *http.Response, req *http.Request) {
+		r.t.Helper()
+		want := "false"
+		if allow {
+			want = "true"
+		}
+		got := res.Header.Get("Access-Control-Allow-Credentials")
+		if got != want {
+			r.t.Fatalf("%s: expected Access-Control-Allow-Credentials %q, got %q", r.name(), want, got)
+		}
+	})
+	return r
+}
+
+// assertHeaderSet parses a comma-separated header value into a set and
+// asserts it matches want exactly, ignoring order and surrounding whitespace.
+func assertHeaderSet(t testing.TB, label, header string, want []string) {
+	t.Helper()
+
+	got := map[string]bool{}
+	for _, part := range strings.Split(header, ",") {
+		part = strings.TrimSpace(part)
+		if part != "" {
+			got[part] = true
+		}
+	}
+
+	wantSet := map[string]bool{}
+	for _, w := range want {
+		wantSet[w] = true
+	}
+
+	if len(got) != len(wantSet) {
+		t.Fatalf("expected %s to be %v, got %v", label, want, header)
+		return
+	}
+	for w := range wantSet {
+		if !got[w] {
+			t.Fatalf("expected %s to be %v, got %v", label, want, header)
+			return
+		}
+	}
+}
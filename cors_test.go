@@ -0,0 +1,60 @@
+package apitest
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestApiTest_PreflightOriginSetsCORSHeaders(t *testing.T) {
+	handler := http.NewServeMux()
+	handler.HandleFunc("/hello", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Origin") != "https://example.com" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.Header.Get("Access-Control-Request-Method") != "PUT" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.Header.Get("Access-Control-Request-Headers") != "X-Custom, Authorization" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", "https://example.com")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, PUT, DELETE")
+		w.Header().Set("Access-Control-Allow-Headers", "X-Custom, Authorization")
+		w.Header().Set("Access-Control-Allow-Credentials", "true")
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	New(handler).
+		Options("/hello").
+		PreflightOrigin("https://example.com", "PUT", "X-Custom", "Authorization").
+		Expect(t).
+		Status(http.StatusNoContent).
+		AllowOrigin("https://example.com").
+		AllowMethods("DELETE", "GET", "PUT").
+		AllowHeaders("Authorization", "X-Custom").
+		AllowCredentials(true).
+		End()
+}
+
+func TestApiTest_AllowMethodsFallsBackToAllowHeader(t *testing.T) {
+	handler := http.NewServeMux()
+	handler.HandleFunc("/hello", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Allow", "GET, POST")
+		w.WriteHeader(http.StatusOK)
+	})
+
+	New(handler).
+		Options("/hello").
+		Expect(t).
+		Status(http.StatusOK).
+		AllowMethods("POST", "GET").
+		End()
+}
+
+func TestAssertHeaderSet_IgnoresOrderAndWhitespace(t *testing.T) {
+	assertHeaderSet(t, "Allow-Methods", "GET,  PUT ,DELETE", []string{"DELETE", "GET", "PUT"})
+}
@@ -0,0 +1,102 @@
+package apitest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// UpdateGolden controls whether Golden assertions (re)write their golden
+// file from the actual response instead of comparing against it. It is a
+// plain bool rather than a registered flag.Bool so importing apitest never
+// conflicts with a consumer's own "-update" flag; wire it up explicitly if
+// wanted:
+//
+//	flag.BoolVar(&apitest.UpdateGolden, "update", false, "update golden files")
+var UpdateGolden bool
+
+// DebugOutput is where Debug dumps request/response output. Override it in
+// tests that want to capture or silence it.
+var DebugOutput io.Writer = os.Stderr
+
+// dumpDebug writes the full request and response to DebugOutput.
+func dumpDebug(req *http.Request, res *http.Response, body []byte) {
+	reqDump, err := httputil.DumpRequest(req, true)
+	if err != nil {
+		reqDump = []byte(fmt.Sprintf("<failed to dump request: %s>", err))
+	}
+	// DumpRequest drains req.Body; restore it so later consumers (e.g. a
+	// custom Assert func) still see the full body.
+	if req.GetBody != nil {
+		if freshBody, err := req.GetBody(); err == nil {
+			req.Body = freshBody
+		}
+	}
+
+	resDump, err := httputil.DumpResponse(res, true)
+	if err != nil {
+		resDump = []byte(fmt.Sprintf("<failed to dump response: %s>", err))
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body))
+
+	fmt.Fprintf(DebugOutput, "--- request ---\n%s\n--- response ---\n%s\n", reqDump, resDump)
+}
+
+// assertGolden compares actual against the golden file at r.goldenPath,
+// rewriting it instead when UpdateGolden is set.
+func (r *Response) assertGolden(actual []byte) {
+	r.t.Helper()
+
+	if UpdateGolden {
+		if err := os.MkdirAll(filepath.Dir(r.goldenPath), 0o755); err != nil {
+			r.t.Fatalf("%s: failed to create golden dir for %s: %s", r.name(), r.goldenPath, err)
+			return
+		}
+		if err := os.WriteFile(r.goldenPath, actual, 0o644); err != nil {
+			r.t.Fatalf("%s: failed to write golden file %s: %s", r.name(), r.goldenPath, err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(r.goldenPath)
+	if err != nil {
+		r.t.Fatalf("%s: failed to read golden file %s: %s", r.name(), r.goldenPath, err)
+		return
+	}
+
+	if !bytes.Equal(want, actual) {
+		r.t.Fatalf("%s: response body does not match golden file %s\n%s",
+			r.name(), r.goldenPath, diffLines(string(want), string(actual)))
+	}
+}
+
+// diffLines renders a line-by-line diff between want and got.
+func diffLines(want, got string) string {
+	wantLines := strings.Split(want, "\n")
+	gotLines := strings.Split(got, "\n")
+
+	n := len(wantLines)
+	if len(gotLines) > n {
+		n = len(gotLines)
+	}
+
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w != g {
+			fmt.Fprintf(&b, "line %d:\n  - %s\n  + %s\n", i+1, w, g)
+		}
+	}
+	return b.String()
+}
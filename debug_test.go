@@ -0,0 +1,129 @@
+package apitest
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestApiTest_DebugDumpsRequestAndResponse(t *testing.T) {
+	handler := http.NewServeMux()
+	handler.HandleFunc("/hello", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("pong"))
+	})
+
+	var out bytes.Buffer
+	old := DebugOutput
+	DebugOutput = &out
+	defer func() { DebugOutput = old }()
+
+	New(handler).
+		Get("/hello").
+		Debug().
+		Expect(t).
+		Status(http.StatusOK).
+		End()
+
+	dump := out.String()
+	if !strings.Contains(dump, "--- request ---") || !strings.Contains(dump, "--- response ---") {
+		t.Fatalf("expected dump to contain request/response sections, got %q", dump)
+	}
+	if !strings.Contains(dump, "GET /hello") {
+		t.Fatalf("expected dump to contain the request line, got %q", dump)
+	}
+	if !strings.Contains(dump, "pong") {
+		t.Fatalf("expected dump to contain the response body, got %q", dump)
+	}
+}
+
+func TestApiTest_GoldenWritesFileUnderUpdate(t *testing.T) {
+	handler := http.NewServeMux()
+	handler.HandleFunc("/hello", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("golden body"))
+	})
+
+	dir := t.TempDir()
+	goldenPath := filepath.Join(dir, "nested", "hello.golden")
+
+	old := UpdateGolden
+	UpdateGolden = true
+	defer func() { UpdateGolden = old }()
+
+	New(handler).
+		Get("/hello").
+		Expect(t).
+		Golden(goldenPath).
+		End()
+
+	got, err := os.ReadFile(goldenPath)
+	if err != nil {
+		t.Fatalf("expected golden file to be written: %s", err)
+	}
+	if string(got) != "golden body" {
+		t.Fatalf("expected golden file to contain %q, got %q", "golden body", string(got))
+	}
+}
+
+func TestApiTest_GoldenMatchesExistingFile(t *testing.T) {
+	handler := http.NewServeMux()
+	handler.HandleFunc("/hello", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("matches"))
+	})
+
+	dir := t.TempDir()
+	goldenPath := filepath.Join(dir, "hello.golden")
+	if err := os.WriteFile(goldenPath, []byte("matches"), 0o644); err != nil {
+		t.Fatalf("failed to seed golden file: %s", err)
+	}
+
+	New(handler).
+		Get("/hello").
+		Expect(t).
+		Golden(goldenPath).
+		End()
+}
+
+func TestApiTest_GoldenReportsDiffOnMismatch(t *testing.T) {
+	handler := http.NewServeMux()
+	handler.HandleFunc("/hello", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("actual body"))
+	})
+
+	dir := t.TempDir()
+	goldenPath := filepath.Join(dir, "hello.golden")
+	if err := os.WriteFile(goldenPath, []byte("expected body"), 0o644); err != nil {
+		t.Fatalf("failed to seed golden file: %s", err)
+	}
+
+	rt := &recordingTB{TB: t}
+	New(handler).
+		Get("/hello").
+		Expect(rt).
+		Golden(goldenPath).
+		End()
+
+	if !rt.failed {
+		t.Fatal("expected a golden mismatch to fail the test")
+	}
+	if !strings.Contains(rt.message, "does not match golden file") {
+		t.Fatalf("expected diff failure message, got %q", rt.message)
+	}
+}
+
+// recordingTB wraps a testing.TB, capturing Fatalf calls instead of aborting
+// the goroutine, so assertion failures can themselves be asserted on.
+type recordingTB struct {
+	testing.TB
+	failed  bool
+	message string
+}
+
+func (r *recordingTB) Fatalf(format string, args ...interface{}) {
+	r.failed = true
+	r.message = fmt.Sprintf(format, args...)
+}
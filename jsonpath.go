@@ -0,0 +1,8 @@
+package apitest
+
+import "github.com/PaesslerAG/jsonpath"
+
+// evalJSONPath evaluates a JSONPath expression against decoded JSON data.
+func evalJSONPath(path string, data interface{}) (interface{}, error) {
+	return jsonpath.Get(path, data)
+}
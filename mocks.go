@@ -0,0 +1,236 @@
+package apitest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sync"
+	"testing"
+)
+
+// Mock declares an outbound HTTP call a handler under test is expected to
+// make, and the canned response to return for it. Install one or more via
+// APITest.Mocks.
+type Mock struct {
+	method      string
+	url         string
+	pathMatches *regexp.Regexp
+	headers     map[string]string
+	body        string
+	hasBody     bool
+
+	times    int
+	anyTimes bool
+
+	mu      sync.Mutex
+	matched int
+
+	response *mockResponse
+}
+
+type mockResponse struct {
+	mock    *Mock
+	status  int
+	headers map[string]string
+	body    string
+}
+
+// NewMock starts the declaration of an expected outbound HTTP call.
+func NewMock() *Mock {
+	return &Mock{headers: map[string]string{}, times: 1}
+}
+
+// Get expects a GET request to url.
+func (m *Mock) Get(url string) *Mock { return m.Method(http.MethodGet, url) }
+
+// Post expects a POST request to url.
+func (m *Mock) Post(url string) *Mock { return m.Method(http.MethodPost, url) }
+
+// Put expects a PUT request to url.
+func (m *Mock) Put(url string) *Mock { return m.Method(http.MethodPut, url) }
+
+// Delete expects a DELETE request to url.
+func (m *Mock) Delete(url string) *Mock { return m.Method(http.MethodDelete, url) }
+
+// Patch expects a PATCH request to url.
+func (m *Mock) Patch(url string) *Mock { return m.Method(http.MethodPatch, url) }
+
+// Method expects a request with the given method and URL.
+func (m *Mock) Method(method, url string) *Mock {
+	m.method = method
+	m.url = url
+	return m
+}
+
+// PathMatches expects the request path to match the given regular
+// expression, instead of an exact URL match.
+func (m *Mock) PathMatches(pattern string) *Mock {
+	m.pathMatches = regexp.MustCompile(pattern)
+	return m
+}
+
+// Header expects the request to carry the given header value.
+func (m *Mock) Header(key, value string) *Mock {
+	m.headers[key] = value
+	return m
+}
+
+// Body expects the request body to equal body exactly.
+func (m *Mock) Body(body string) *Mock {
+	m.body = body
+	m.hasBody = true
+	return m
+}
+
+// Times sets the exact number of times this mock must be matched. Defaults
+// to 1.
+func (m *Mock) Times(n int) *Mock {
+	m.times = n
+	return m
+}
+
+// AnyTimes allows this mock to be matched any number of times, including
+// zero.
+func (m *Mock) AnyTimes() *Mock {
+	m.anyTimes = true
+	return m
+}
+
+// RespondWith starts the declaration of the canned response returned when
+// this mock matches an outbound request.
+func (m *Mock) RespondWith() *mockResponse {
+	return &mockResponse{mock: m, headers: map[string]string{}, status: http.StatusOK}
+}
+
+// Status sets the status code of the mocked response.
+func (mr *mockResponse) Status(status int) *mockResponse {
+	mr.status = status
+	return mr
+}
+
+// Header sets a header on the mocked response.
+func (mr *mockResponse) Header(key, value string) *mockResponse {
+	mr.headers[key] = value
+	return mr
+}
+
+// Body sets the body of the mocked response.
+func (mr *mockResponse) Body(body string) *mockResponse {
+	mr.body = body
+	return mr
+}
+
+// End finishes the mock declaration and returns it, ready to be passed to
+// APITest.Mocks.
+func (mr *mockResponse) End() *Mock {
+	mr.mock.response = mr
+	return mr.mock
+}
+
+// matches reports whether req satisfies this mock's declared expectations.
+func (m *Mock) matches(req *http.Request) bool {
+	if m.method != "" && m.method != req.Method {
+		return false
+	}
+
+	if m.pathMatches != nil {
+		if !m.pathMatches.MatchString(req.URL.Path) {
+			return false
+		}
+	} else if m.url != "" && m.url != req.URL.String() {
+		return false
+	}
+
+	for key, value := range m.headers {
+		if req.Header.Get(key) != value {
+			return false
+		}
+	}
+
+	if m.hasBody {
+		actual, _ := io.ReadAll(req.Body)
+		req.Body = io.NopCloser(bytes.NewReader(actual))
+		if string(actual) != m.body {
+			return false
+		}
+	}
+
+	return true
+}
+
+// buildResponse synthesizes the *http.Response declared by this mock for
+// req.
+func (m *Mock) buildResponse(req *http.Request) *http.Response {
+	header := http.Header{}
+	for k, v := range m.response.headers {
+		header.Set(k, v)
+	}
+	return &http.Response{
+		StatusCode: m.response.status,
+		Header:     header,
+		Body:       io.NopCloser(bytes.NewBufferString(m.response.body)),
+		Request:    req,
+	}
+}
+
+// assertSatisfied fails t if this mock was not matched the expected number
+// of times.
+func (m *Mock) assertSatisfied(t testing.TB) {
+	t.Helper()
+	if m.anyTimes {
+		return
+	}
+	m.mu.Lock()
+	matched := m.matched
+	m.mu.Unlock()
+	if matched != m.times {
+		t.Fatalf("apitest: mock %s %s expected %d call(s), got %d", m.method, m.url, m.times, matched)
+	}
+}
+
+// mockTransport is installed as http.DefaultTransport for the duration of a
+// single APITest.End call so that outbound requests made by the handler
+// under test are matched against declared mocks instead of hitting the
+// network.
+type mockTransport struct {
+	t     testing.TB
+	mocks []*Mock
+}
+
+// RoundTrip implements http.RoundTripper.
+func (rt *mockTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for _, m := range rt.mocks {
+		if m.matches(req) {
+			m.mu.Lock()
+			m.matched++
+			m.mu.Unlock()
+			return m.buildResponse(req), nil
+		}
+	}
+
+	rt.t.Fatalf("apitest: unexpected outbound request %s %s did not match any declared mock", req.Method, req.URL)
+	return nil, fmt.Errorf("apitest: unexpected outbound request %s %s", req.Method, req.URL)
+}
+
+// mockTransportMu serializes swaps of the process-global
+// http.DefaultTransport so that concurrent tests using .Mocks() (e.g. under
+// t.Parallel()) don't race on installing/restoring it or observe each
+// other's mocks. Each call to .End() that needs mocking holds the lock for
+// the full duration of its request, including restore.
+var mockTransportMu sync.Mutex
+
+// installMockTransport swaps http.DefaultTransport for one that serves the
+// given mocks, returning a func to restore the original transport. It
+// blocks until any other in-flight mocked request has finished and been
+// restored.
+func installMockTransport(t testing.TB, mocks []*Mock) func() {
+	mockTransportMu.Lock()
+	original := http.DefaultTransport
+	http.DefaultTransport = &mockTransport{t: t, mocks: mocks}
+	return func() {
+		http.DefaultTransport = original
+		mockTransportMu.Unlock()
+	}
+}
@@ -0,0 +1,68 @@
+package apitest
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestApiTest_MocksStubOutboundCalls(t *testing.T) {
+	handler := http.NewServeMux()
+	handler.HandleFunc("/hello", func(w http.ResponseWriter, r *http.Request) {
+		res, err := http.Get("https://api.example.com/users/42")
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		defer res.Body.Close()
+
+		body, _ := ioutil.ReadAll(res.Body)
+		w.WriteHeader(res.StatusCode)
+		_, _ = w.Write(body)
+	})
+
+	New(handler).
+		Mocks(
+			NewMock().
+				Get("https://api.example.com/users/42").
+				RespondWith().
+				Status(http.StatusOK).
+				Body(`{"id":42}`).
+				End(),
+		).
+		Get("/hello").
+		Expect(t).
+		Status(http.StatusOK).
+		Body(`{"id":42}`).
+		End()
+}
+
+func TestApiTest_MocksMatchOnHeaderAndAnyTimes(t *testing.T) {
+	handler := http.NewServeMux()
+	handler.HandleFunc("/hello", func(w http.ResponseWriter, r *http.Request) {
+		req, _ := http.NewRequest(http.MethodGet, "https://api.example.com/ping", nil)
+		req.Header.Set("Authorization", "Bearer token")
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		defer res.Body.Close()
+		w.WriteHeader(res.StatusCode)
+	})
+
+	New(handler).
+		Mocks(
+			NewMock().
+				Get("https://api.example.com/ping").
+				Header("Authorization", "Bearer token").
+				AnyTimes().
+				RespondWith().
+				Status(http.StatusNoContent).
+				End(),
+		).
+		Get("/hello").
+		Expect(t).
+		Status(http.StatusNoContent).
+		End()
+}
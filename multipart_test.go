@@ -0,0 +1,100 @@
+package apitest
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestApiTest_SendsFormUrlencodedData(t *testing.T) {
+	handler := http.NewServeMux()
+	handler.HandleFunc("/hello", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.Form.Get("a") != "b" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	New(handler).
+		Post("/hello").
+		FormData(map[string]string{"a": "b"}).
+		Expect(t).
+		Status(http.StatusOK).
+		End()
+}
+
+func TestApiTest_SendsMultipartFormDataWithRealFilename(t *testing.T) {
+	handler := http.NewServeMux()
+	handler.HandleFunc("/hello", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1024); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.FormValue("name") != "gopher" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+
+		if header.Filename != "report.csv" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		contents, _ := ioutil.ReadAll(file)
+		if string(contents) != "a,b,c" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	})
+
+	New(handler).
+		Post("/hello").
+		MultipartFormData(
+			map[string]string{"name": "gopher"},
+			map[string]MultipartFile{"file": {Filename: "report.csv", Reader: strings.NewReader("a,b,c")}},
+		).
+		Expect(t).
+		Status(http.StatusOK).
+		End()
+}
+
+func TestApiTest_FileUpload(t *testing.T) {
+	handler := http.NewServeMux()
+	handler.HandleFunc("/hello", func(w http.ResponseWriter, r *http.Request) {
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+
+		if header.Filename != "avatar.png" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	})
+
+	New(handler).
+		Post("/hello").
+		FileUpload("file", "avatar.png", strings.NewReader("binary-data")).
+		Expect(t).
+		Status(http.StatusOK).
+		End()
+}
@@ -0,0 +1,27 @@
+package apitest
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// FromRecorder builds a Response directly from a populated
+// httptest.ResponseRecorder and the request that produced it, bypassing
+// New/Expect. It supports the same assertion surface (Body, BodyText,
+// Headers, Cookies, CookiePresent, JSONPath, Assert), so lower-level code
+// paths that don't go through an http.Handler — middleware composed
+// outside of one, or handlers driven directly with a ResponseWriter — can
+// still be asserted on fluently.
+func FromRecorder(t testing.TB, rec *httptest.ResponseRecorder, req *http.Request) *Response {
+	res := &http.Response{
+		StatusCode: rec.Code,
+		Header:     rec.HeaderMap,
+		Body:       io.NopCloser(bytes.NewReader(rec.Body.Bytes())),
+		Request:    req,
+	}
+
+	return &Response{t: t, req: req, res: res}
+}
@@ -0,0 +1,42 @@
+package apitest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFromRecorder_SupportsExistingAssertions(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/hello", nil)
+	rec := httptest.NewRecorder()
+	rec.Header().Set("Content-Type", "application/json")
+	rec.WriteHeader(http.StatusCreated)
+	_, err := rec.Write([]byte(`{"a": 12345}`))
+	if err != nil {
+		panic(err)
+	}
+
+	FromRecorder(t, rec, req).
+		Status(http.StatusCreated).
+		Body(`{"a": 12345}`).
+		Headers(map[string]string{"Content-Type": "application/json"}).
+		End()
+}
+
+func TestFromRecorder_SupportsJSONPath(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/hello", nil)
+	rec := httptest.NewRecorder()
+	rec.WriteHeader(http.StatusOK)
+	_, err := rec.Write([]byte(`{"a": 12345}`))
+	if err != nil {
+		panic(err)
+	}
+
+	FromRecorder(t, rec, req).
+		JSONPath(`$.a`, func(value interface{}) {
+			if value != 12345.0 {
+				t.Fatalf("expected 12345, got %v", value)
+			}
+		}).
+		End()
+}
@@ -0,0 +1,81 @@
+package apitest
+
+import (
+	"net/http"
+	"net/http/cookiejar"
+)
+
+// Session threads a cookie jar and default request settings across a
+// sequence of requests against the same handler, mirroring the
+// login-then-navigate pattern used by auth-gated integration suites.
+type Session struct {
+	handler   http.Handler
+	jar       http.CookieJar
+	headers   map[string]string
+	basicAuth string
+}
+
+// NewSession creates a Session backed by an in-memory cookie jar. Cookies
+// set by one request are automatically replayed on subsequent requests
+// through the same Session.
+func NewSession(handler http.Handler) *Session {
+	jar, _ := cookiejar.New(nil)
+	return &Session{
+		handler: handler,
+		jar:     jar,
+		headers: map[string]string{},
+	}
+}
+
+// Headers sets default headers applied to every request made through the
+// session.
+func (s *Session) Headers(headers map[string]string) *Session {
+	for k, v := range headers {
+		s.headers[k] = v
+	}
+	return s
+}
+
+// BasicAuth sets default basic auth credentials applied to every request
+// made through the session.
+func (s *Session) BasicAuth(auth string) *Session {
+	s.basicAuth = auth
+	return s
+}
+
+// Get starts a GET request through the session.
+func (s *Session) Get(url string) *APITest {
+	return s.newRequest().Get(url)
+}
+
+// Post starts a POST request through the session.
+func (s *Session) Post(url string) *APITest {
+	return s.newRequest().Post(url)
+}
+
+// Put starts a PUT request through the session.
+func (s *Session) Put(url string) *APITest {
+	return s.newRequest().Put(url)
+}
+
+// Delete starts a DELETE request through the session.
+func (s *Session) Delete(url string) *APITest {
+	return s.newRequest().Delete(url)
+}
+
+// Patch starts a PATCH request through the session.
+func (s *Session) Patch(url string) *APITest {
+	return s.newRequest().Patch(url)
+}
+
+// newRequest returns an APITest pre-configured with the session's jar and
+// defaults, ready for a method + path to be set on it.
+func (s *Session) newRequest() *APITest {
+	a := New(s.handler)
+	a.jar = s.jar
+	a.Headers(s.headers)
+	if s.basicAuth != "" {
+		a.BasicAuth(s.basicAuth)
+	}
+	return a
+}
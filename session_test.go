@@ -0,0 +1,52 @@
+package apitest
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestSession_CarriesCookiesAcrossRequests(t *testing.T) {
+	handler := http.NewServeMux()
+	handler.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc123"})
+		w.WriteHeader(http.StatusOK)
+	})
+	handler.HandleFunc("/profile", func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie("session")
+		if err != nil || cookie.Value != "abc123" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	session := NewSession(handler)
+
+	session.Get("/login").
+		Expect(t).
+		Status(http.StatusOK).
+		End()
+
+	session.Get("/profile").
+		Expect(t).
+		Status(http.StatusOK).
+		End()
+}
+
+func TestSession_AppliesDefaultHeaders(t *testing.T) {
+	handler := http.NewServeMux()
+	handler.HandleFunc("/hello", func(w http.ResponseWriter, r *http.Request) {
+		if "12345" != r.Header.Get("My-Header") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	session := NewSession(handler).Headers(map[string]string{"My-Header": "12345"})
+
+	session.Get("/hello").
+		Expect(t).
+		Status(http.StatusOK).
+		End()
+}